@@ -0,0 +1,132 @@
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cli/cli/internal/ghrepo"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	runsBucket        = []byte("runs")
+	jobsBucket        = []byte("jobs")
+	annotationsBucket = []byte("annotations")
+)
+
+// Cache persists runs, jobs, and annotations fetched from the API into a
+// local BoltDB file, so a run can later be reviewed offline or without
+// re-paying the cost of re-fetching it.
+type Cache struct {
+	db *bolt.DB
+}
+
+// DefaultCachePath returns the BoltDB file `gh run view` reads from and
+// writes to. Set GH_RUN_CACHE to point multiple machines at a shared file.
+func DefaultCachePath() (string, error) {
+	if path := os.Getenv("GH_RUN_CACHE"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gh", "run-cache.db"), nil
+}
+
+// OpenCache opens the BoltDB cache at path, creating the file and its
+// buckets if they don't already exist.
+func OpenCache(path string) (*Cache, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{runsBucket, jobsBucket, annotationsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Cache{db: db}, nil
+}
+
+// Close releases the underlying BoltDB file.
+func (c *Cache) Close() error {
+	return c.db.Close()
+}
+
+func runCacheKey(repo ghrepo.Interface, runID string) []byte {
+	return []byte(fmt.Sprintf("%s/%s/%s", repo.RepoOwner(), repo.RepoName(), runID))
+}
+
+// SaveRun writes run, its jobs, and its annotations into the cache, keyed by
+// owner/repo/runID, overwriting anything previously cached for that run.
+func (c *Cache) SaveRun(repo ghrepo.Interface, run Run, jobs []Job, annotations []Annotation) error {
+	key := runCacheKey(repo, fmt.Sprintf("%d", run.ID))
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		if err := putJSON(tx.Bucket(runsBucket), key, run); err != nil {
+			return err
+		}
+		if err := putJSON(tx.Bucket(jobsBucket), key, jobs); err != nil {
+			return err
+		}
+		return putJSON(tx.Bucket(annotationsBucket), key, annotations)
+	})
+}
+
+// LoadRun reads a previously cached run, along with its jobs and
+// annotations, returning an error if nothing is cached for that run.
+func (c *Cache) LoadRun(repo ghrepo.Interface, runID string) (*Run, []Job, []Annotation, error) {
+	key := runCacheKey(repo, runID)
+
+	var run Run
+	var jobs []Job
+	var annotations []Annotation
+
+	err := c.db.View(func(tx *bolt.Tx) error {
+		if err := getJSON(tx.Bucket(runsBucket), key, &run); err != nil {
+			return err
+		}
+		if err := getJSON(tx.Bucket(jobsBucket), key, &jobs); err != nil {
+			return err
+		}
+		return getJSON(tx.Bucket(annotationsBucket), key, &annotations)
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return &run, jobs, annotations, nil
+}
+
+func putJSON(b *bolt.Bucket, key []byte, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, data)
+}
+
+func getJSON(b *bolt.Bucket, key []byte, v interface{}) error {
+	data := b.Get(key)
+	if data == nil {
+		return fmt.Errorf("no cache entry for %q", key)
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,40 @@
+package shared
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cli/cli/internal/ghrepo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheSaveAndLoadRun(t *testing.T) {
+	repo := ghrepo.New("OWNER", "REPO")
+	run := Run{ID: 123, Name: "CI", HeadSha: "deadbeef"}
+	jobs := []Job{{ID: 1, Name: "build"}}
+	annotations := []Annotation{{Message: "oops", Path: "main.go", StartLine: 10}}
+
+	cache, err := OpenCache(filepath.Join(t.TempDir(), "run-cache.db"))
+	require.NoError(t, err)
+	defer cache.Close()
+
+	require.NoError(t, cache.SaveRun(repo, run, jobs, annotations))
+
+	gotRun, gotJobs, gotAnnotations, err := cache.LoadRun(repo, "123")
+	require.NoError(t, err)
+	assert.Equal(t, run, *gotRun)
+	assert.Equal(t, jobs, gotJobs)
+	assert.Equal(t, annotations, gotAnnotations)
+}
+
+func TestCacheLoadRun_missingEntry(t *testing.T) {
+	repo := ghrepo.New("OWNER", "REPO")
+
+	cache, err := OpenCache(filepath.Join(t.TempDir(), "run-cache.db"))
+	require.NoError(t, err)
+	defer cache.Close()
+
+	_, _, _, err = cache.LoadRun(repo, "999")
+	assert.Error(t, err)
+}
@@ -0,0 +1,44 @@
+package shared
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/cli/cli/api"
+	"github.com/cli/cli/internal/ghinstance"
+	"github.com/cli/cli/internal/ghrepo"
+)
+
+// JobLogRange fetches job's log starting at byte offset, using an HTTP
+// Range request so that repeated `--follow` polls only transfer the bytes
+// appended since the last poll instead of redownloading the whole log.
+func JobLogRange(client *api.Client, repo ghrepo.Interface, job Job, offset int64) (io.ReadCloser, error) {
+	u := fmt.Sprintf("%srepos/%s/%s/actions/jobs/%d/logs",
+		ghinstance.RESTPrefix(repo.RepoHost()), repo.RepoOwner(), repo.RepoName(), job.ID)
+
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := client.HTTP().Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusPartialContent:
+		return resp.Body, nil
+	case http.StatusRequestedRangeNotSatisfiable:
+		// Nothing new past offset yet.
+		resp.Body.Close()
+		return http.NoBody, nil
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch job log: %s", resp.Status)
+	}
+}
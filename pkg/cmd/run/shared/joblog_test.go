@@ -0,0 +1,82 @@
+package shared
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/cli/cli/api"
+	"github.com/cli/cli/internal/ghrepo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// redirectTransport rewrites every request to point at a local test server,
+// so JobLogRange's hardcoded api.github.com-style URL still lands somewhere
+// we can assert against.
+type redirectTransport struct {
+	base *url.URL
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = t.base.Scheme
+	req.URL.Host = t.base.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestJobLogRange(t *testing.T) {
+	const fullLog = "line one\nline two\nline three\n"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rangeHeader := r.Header.Get("Range")
+		if rangeHeader == "" {
+			w.WriteHeader(http.StatusOK)
+			_, _ = io.WriteString(w, fullLog)
+			return
+		}
+
+		var start int
+		if _, err := fmt.Sscanf(rangeHeader, "bytes=%d-", &start); err != nil {
+			http.Error(w, "bad range", http.StatusBadRequest)
+			return
+		}
+		if start >= len(fullLog) {
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = io.WriteString(w, fullLog[start:])
+	}))
+	defer ts.Close()
+
+	base, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	client := api.NewClientFromHTTP(&http.Client{Transport: &redirectTransport{base: base}})
+	repo := ghrepo.New("OWNER", "REPO")
+	job := Job{ID: 1}
+
+	r, err := JobLogRange(client, repo, job, 0)
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, fullLog, string(content))
+
+	r, err = JobLogRange(client, repo, job, int64(len("line one\n")))
+	require.NoError(t, err)
+	content, err = io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, "line two\nline three\n", string(content))
+
+	r, err = JobLogRange(client, repo, job, int64(len(fullLog)))
+	require.NoError(t, err)
+	content, err = io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Empty(t, content)
+}
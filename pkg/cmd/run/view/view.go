@@ -1,39 +1,78 @@
 package view
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/MakeNowJust/heredoc"
 	"github.com/cli/cli/api"
 	"github.com/cli/cli/internal/ghrepo"
 	"github.com/cli/cli/pkg/cmd/run/shared"
 	"github.com/cli/cli/pkg/cmdutil"
+	"github.com/cli/cli/pkg/cmdutil/browser"
 	"github.com/cli/cli/pkg/iostreams"
 	"github.com/cli/cli/utils"
 	"github.com/spf13/cobra"
 )
 
+// pollIntervalMin is the floor on how often we hit the API while following a
+// run; the interval backs off up to pollIntervalMax on consecutive empty polls.
+const (
+	pollIntervalMin = 2 * time.Second
+	pollIntervalMax = 10 * time.Second
+)
+
+var runFields = []string{
+	"name",
+	"databaseId",
+	"workflowName",
+	"headBranch",
+	"headSha",
+	"status",
+	"conclusion",
+	"event",
+	"createdAt",
+	"url",
+	"jobs",
+	"annotations",
+}
+
 type ViewOptions struct {
 	HttpClient func() (*http.Client, error)
 	IO         *iostreams.IOStreams
 	BaseRepo   func() (ghrepo.Interface, error)
+	Browser    browser.Browser
 
 	RunID      string
 	Verbose    bool
 	ExitStatus bool
+	Follow     bool
+	NoPR       bool
+	ShowSource bool
+	Offline    bool
+	Web        bool
+	Format     string
 
 	Prompt       bool
 	ShowProgress bool
 
 	Now func() time.Time
+
+	Exporter cmdutil.Exporter
 }
 
 func NewCmdView(f *cmdutil.Factory, runF func(*ViewOptions) error) *cobra.Command {
 	opts := &ViewOptions{
 		IO:         f.IOStreams,
 		HttpClient: f.HttpClient,
+		Browser:    f.Browser,
 		Now:        time.Now,
 	}
 	cmd := &cobra.Command{
@@ -66,6 +105,10 @@ func NewCmdView(f *cmdutil.Factory, runF func(*ViewOptions) error) *cobra.Comman
 				opts.Prompt = true
 			}
 
+			if opts.Format != "" && opts.Format != "markdown" {
+				return &cmdutil.FlagError{Err: fmt.Errorf("unsupported --format %q: only %q is supported", opts.Format, "markdown")}
+			}
+
 			if runF != nil {
 				return runF(opts)
 			}
@@ -75,21 +118,32 @@ func NewCmdView(f *cmdutil.Factory, runF func(*ViewOptions) error) *cobra.Comman
 	cmd.Flags().BoolVarP(&opts.Verbose, "verbose", "v", false, "Show job steps")
 	// TODO should we try and expose pending via another exit code?
 	cmd.Flags().BoolVarP(&opts.ExitStatus, "exit-status", "e", false, "Exit with non-zero status if run failed")
+	cmd.Flags().BoolVarP(&opts.Follow, "follow", "f", false, "Watch a run in progress, streaming new log output as it happens")
+	cmd.Flags().BoolVar(&opts.NoPR, "no-pr", false, "Skip looking up the pull request associated with the run")
+	cmd.Flags().BoolVarP(&opts.ShowSource, "show-source", "s", false, "Show source code excerpts for annotations")
+	cmd.Flags().BoolVar(&opts.Offline, "offline", false, "Read a previously cached run instead of hitting the API")
+	cmd.Flags().BoolVarP(&opts.Web, "web", "w", false, "Open run in the browser")
+	cmd.Flags().StringVar(&opts.Format, "format", "", "Render the run as a self-contained Markdown report")
+	cmdutil.AddJSONFlags(cmd, &opts.Exporter, runFields)
 
 	return cmd
 }
 
 func runView(opts *ViewOptions) error {
-	c, err := opts.HttpClient()
+	repo, err := opts.BaseRepo()
 	if err != nil {
-		return fmt.Errorf("failed to create http client: %w", err)
+		return fmt.Errorf("failed to determine base repo: %w", err)
 	}
-	client := api.NewClientFromHTTP(c)
 
-	repo, err := opts.BaseRepo()
+	if opts.Offline {
+		return runViewOffline(opts, repo)
+	}
+
+	c, err := opts.HttpClient()
 	if err != nil {
-		return fmt.Errorf("failed to determine base repo: %w", err)
+		return fmt.Errorf("failed to create http client: %w", err)
 	}
+	client := api.NewClientFromHTTP(c)
 
 	runID := opts.RunID
 
@@ -106,45 +160,176 @@ func runView(opts *ViewOptions) error {
 	}
 	run, err := shared.GetRun(client, repo, runID)
 	if err != nil {
+		if opts.ShowProgress {
+			opts.IO.StopProgressIndicator()
+		}
 		return fmt.Errorf("failed to get run: %w", err)
 	}
 
-	jobs, err := shared.GetJobs(client, repo, *run)
-	if err != nil {
-		return fmt.Errorf("failed to get jobs: %w", err)
+	if opts.Web {
+		if opts.ShowProgress {
+			opts.IO.StopProgressIndicator()
+		}
+		if opts.IO.IsStdoutTTY() {
+			fmt.Fprintf(opts.IO.ErrOut, "Opening %s in your browser.\n", utils.DisplayURL(run.URL))
+		}
+		return opts.Browser.Browse(run.URL)
 	}
 
-	var annotations []shared.Annotation
-
-	var annotationErr error
-	var as []shared.Annotation
-	for _, job := range jobs {
-		as, annotationErr = shared.GetAnnotations(client, repo, job)
-		if annotationErr != nil {
-			break
+	if opts.Follow && run.Status != shared.Completed {
+		if opts.ShowProgress {
+			opts.IO.StopProgressIndicator()
 		}
-		annotations = append(annotations, as...)
+		return followRun(*opts, client, repo, run)
 	}
 
-	if annotationErr != nil {
-		return fmt.Errorf("failed to get annotations: %w", annotationErr)
+	jobs, annotations, err := getRunData(client, repo, *run)
+	if err == nil && !opts.NoPR {
+		// Warm the PR cache now, while the progress indicator is still
+		// spinning, so the render path's title lookup is instant instead of
+		// blocking on a GraphQL/REST round-trip after we've stopped showing
+		// any feedback to the user.
+		_, _ = pullRequestForRun(client, repo, *run)
 	}
-
 	if opts.ShowProgress {
 		opts.IO.StopProgressIndicator()
 	}
-	err = renderRun(*opts, *run, jobs, annotations)
 	if err != nil {
 		return err
 	}
 
+	cacheRun(repo, *run, jobs, annotations)
+
+	if opts.Exporter != nil {
+		if err := opts.Exporter.Write(opts.IO, &exportedRun{Run: *run, Jobs: jobs, Annotations: annotations}); err != nil {
+			return err
+		}
+		return exitStatusErr(opts, *run)
+	}
+
+	if opts.Format == "markdown" {
+		if err := renderRunMarkdown(opts.IO.Out, repo, *run, jobs, annotations); err != nil {
+			return err
+		}
+		return exitStatusErr(opts, *run)
+	}
+
+	return renderRun(*opts, client, repo, *run, jobs, annotations)
+}
+
+// exitStatusErr returns cmdutil.SilentError when --exit-status was requested
+// and the run failed, matching the check renderRun and followRun already
+// apply to the human-readable output path.
+func exitStatusErr(opts *ViewOptions, run shared.Run) error {
+	if opts.ExitStatus && shared.IsFailureState(run.Conclusion) {
+		return cmdutil.SilentError
+	}
 	return nil
 }
 
-func titleForRun(cs *iostreams.ColorScheme, run shared.Run) string {
-	// TODO how to obtain? i can get a SHA but it's not immediately clear how to get from sha -> pr
-	// without a ton of hops
+// runViewOffline serves a run entirely from the local cache, for reviewing
+// past failures without a network connection. Since there's no API access,
+// the pull-request lookup is skipped regardless of --no-pr.
+func runViewOffline(opts *ViewOptions, repo ghrepo.Interface) error {
+	if opts.RunID == "" {
+		return &cmdutil.FlagError{Err: errors.New("run ID required when using --offline")}
+	}
+
+	cachePath, err := shared.DefaultCachePath()
+	if err != nil {
+		return err
+	}
+	cache, err := shared.OpenCache(cachePath)
+	if err != nil {
+		return fmt.Errorf("failed to open run cache: %w", err)
+	}
+	defer cache.Close()
+
+	run, jobs, annotations, err := cache.LoadRun(repo, opts.RunID)
+	if err != nil {
+		return fmt.Errorf("no cached data for run %s: %w", opts.RunID, err)
+	}
+
+	if opts.Exporter != nil {
+		if err := opts.Exporter.Write(opts.IO, &exportedRun{Run: *run, Jobs: jobs, Annotations: annotations}); err != nil {
+			return err
+		}
+		return exitStatusErr(opts, *run)
+	}
+
+	if opts.Format == "markdown" {
+		if err := renderRunMarkdown(opts.IO.Out, repo, *run, jobs, annotations); err != nil {
+			return err
+		}
+		return exitStatusErr(opts, *run)
+	}
+
+	opts.NoPR = true
+	return renderRun(*opts, nil, repo, *run, jobs, annotations)
+}
+
+// cacheRun best-effort persists a freshly-fetched run so it can later be
+// reviewed with --offline. A cache failure shouldn't prevent the run from
+// being shown, so errors are swallowed.
+func cacheRun(repo ghrepo.Interface, run shared.Run, jobs []shared.Job, annotations []shared.Annotation) {
+	cachePath, err := shared.DefaultCachePath()
+	if err != nil {
+		return
+	}
+	cache, err := shared.OpenCache(cachePath)
+	if err != nil {
+		return
+	}
+	defer cache.Close()
+
+	_ = cache.SaveRun(repo, run, jobs, annotations)
+}
+
+// getRunData fetches a run's jobs and the annotations for each of its jobs.
+func getRunData(client *api.Client, repo ghrepo.Interface, run shared.Run) ([]shared.Job, []shared.Annotation, error) {
+	jobs, err := shared.GetJobs(client, repo, run)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get jobs: %w", err)
+	}
+
+	var annotations []shared.Annotation
+	for _, job := range jobs {
+		as, err := shared.GetAnnotations(client, repo, job)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get annotations: %w", err)
+		}
+		annotations = append(annotations, as...)
+	}
+
+	return jobs, annotations, nil
+}
+
+// exportedRun is the shape written out for --json/--jq/--template; it bundles
+// a run together with the jobs and annotations that renderRun would otherwise
+// print as separate sections.
+type exportedRun struct {
+	shared.Run
+	Jobs        []shared.Job        `json:"jobs"`
+	Annotations []shared.Annotation `json:"annotations"`
+}
+
+// prForRunCache memoizes the sha -> PR lookup so that repeated lookups for the
+// same run (e.g. runView's warm-up followed by the title render) don't refetch it.
+var prForRunCache sync.Map
+
+// runPR is the subset of a pull request's fields needed to annotate a run's title.
+type runPR struct {
+	Number int
+	State  string
+}
+
+func titleForRun(client *api.Client, repo ghrepo.Interface, cs *iostreams.ColorScheme, run shared.Run, noPR bool) string {
 	prID := ""
+	if !noPR {
+		if pr, err := pullRequestForRun(client, repo, run); err == nil && pr != nil {
+			prID = fmt.Sprintf(" %s", cs.ColorFromString(prStateColor(pr.State))(fmt.Sprintf("#%d", pr.Number)))
+		}
+	}
 
 	return fmt.Sprintf("%s %s%s",
 		cs.Bold(run.HeadBranch),
@@ -152,13 +337,272 @@ func titleForRun(cs *iostreams.ColorScheme, run shared.Run) string {
 		prID)
 }
 
+func prStateColor(state string) string {
+	switch state {
+	case "MERGED":
+		return "magenta"
+	case "CLOSED":
+		return "red"
+	default:
+		return "green"
+	}
+}
+
+// pullRequestForRun resolves the pull request associated with a run's head
+// commit, preferring a single GraphQL round-trip and falling back to a REST
+// search when the commit isn't reachable via associatedPullRequests (e.g.
+// the PR's source branch has since been deleted).
+func pullRequestForRun(client *api.Client, repo ghrepo.Interface, run shared.Run) (*runPR, error) {
+	cacheKey := fmt.Sprintf("%s/%s#%d", repo.RepoOwner(), repo.RepoName(), run.ID)
+	if cached, ok := prForRunCache.Load(cacheKey); ok {
+		pr, _ := cached.(*runPR)
+		return pr, nil
+	}
+
+	pr, err := pullRequestForSHAGraphQL(client, repo, run.HeadSha)
+	if err != nil || pr == nil {
+		// associatedPullRequests can come back empty for a commit whose PR
+		// branch has since been deleted; the REST search still finds those.
+		restPR, restErr := pullRequestForSHAREST(client, repo, run.HeadSha)
+		if restErr != nil {
+			if err != nil {
+				return nil, err
+			}
+			return nil, restErr
+		}
+		pr = restPR
+	}
+
+	prForRunCache.Store(cacheKey, pr)
+	return pr, nil
+}
+
+const pullRequestForSHAQuery = `
+query PullRequestForRunSHA($owner: String!, $repo: String!, $sha: GitObjectID!) {
+	repository(owner: $owner, name: $repo) {
+		object(oid: $sha) {
+			... on Commit {
+				associatedPullRequests(first: 1) {
+					nodes {
+						number
+						state
+					}
+				}
+			}
+		}
+	}
+}`
+
+func pullRequestForSHAGraphQL(client *api.Client, repo ghrepo.Interface, sha string) (*runPR, error) {
+	variables := map[string]interface{}{
+		"owner": repo.RepoOwner(),
+		"repo":  repo.RepoName(),
+		"sha":   sha,
+	}
+
+	var resp struct {
+		Repository struct {
+			Object struct {
+				AssociatedPullRequests struct {
+					Nodes []struct {
+						Number int
+						State  string
+					}
+				}
+			}
+		}
+	}
+
+	if err := client.GraphQL(repo.RepoHost(), pullRequestForSHAQuery, variables, &resp); err != nil {
+		return nil, err
+	}
+
+	nodes := resp.Repository.Object.AssociatedPullRequests.Nodes
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+	return &runPR{Number: nodes[0].Number, State: nodes[0].State}, nil
+}
+
+func pullRequestForSHAREST(client *api.Client, repo ghrepo.Interface, sha string) (*runPR, error) {
+	var result struct {
+		Items []struct {
+			Number int    `json:"number"`
+			State  string `json:"state"`
+		} `json:"items"`
+	}
+
+	q := url.QueryEscape(fmt.Sprintf("type:pr sha:%s", sha))
+	if err := client.REST(repo.RepoHost(), "GET", fmt.Sprintf("search/issues?q=%s", q), nil, &result); err != nil {
+		return nil, err
+	}
+	if len(result.Items) == 0 {
+		return nil, nil
+	}
+	return &runPR{Number: result.Items[0].Number, State: result.Items[0].State}, nil
+}
+
+// sourceFileKey identifies a single fetch of a file's contents at a given commit.
+type sourceFileKey struct {
+	path string
+	sha  string
+}
+
+// sourceFileCache memoizes file contents by (path, sha) so that several
+// annotations pointing at the same file only cost one contents-API fetch.
+var sourceFileCache sync.Map
+
+// renderAnnotationSource prints a compiler-style excerpt around the
+// annotation's offending line: up to two numbered lines of context on
+// either side, with a "> " marker on the reported line. The path#line
+// header above it is printed by renderRun.
+func renderAnnotationSource(out io.Writer, cs *iostreams.ColorScheme, client *api.Client, repo ghrepo.Interface, sha string, a shared.Annotation) {
+	if client == nil {
+		// No API access (e.g. --offline); nothing to fetch the source from.
+		return
+	}
+
+	lines, err := fetchSourceLines(client, repo, sha, a.Path)
+	if err != nil || len(lines) == 0 {
+		return
+	}
+
+	start := a.StartLine - 2
+	if start < 1 {
+		start = 1
+	}
+	end := a.StartLine + 2
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	for ln := start; ln <= end; ln++ {
+		marker := "  "
+		if ln == a.StartLine {
+			marker = cs.Red("> ")
+		}
+		fmt.Fprintf(out, "%s%4d | %s\n", marker, ln, lines[ln-1])
+	}
+	fmt.Fprintln(out)
+}
+
+// escapeContentsPath percent-encodes each segment of a repo-relative path
+// for use in the contents API URL, without escaping the "/" separators
+// (annotation paths can contain spaces, "#", "?", "&", etc).
+func escapeContentsPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// fetchSourceLines returns the lines of path as it existed at sha, fetched
+// via the repository contents API and cached per (path, sha).
+func fetchSourceLines(client *api.Client, repo ghrepo.Interface, sha, path string) ([]string, error) {
+	key := sourceFileKey{path: path, sha: sha}
+	if cached, ok := sourceFileCache.Load(key); ok {
+		lines, _ := cached.([]string)
+		return lines, nil
+	}
+
+	var result struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	p := fmt.Sprintf("repos/%s/%s/contents/%s?ref=%s",
+		repo.RepoOwner(), repo.RepoName(), escapeContentsPath(path), url.QueryEscape(sha))
+	if err := client.REST(repo.RepoHost(), "GET", p, nil, &result); err != nil {
+		return nil, err
+	}
+
+	content := result.Content
+	if result.Encoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(content, "\n", ""))
+		if err != nil {
+			return nil, err
+		}
+		content = string(decoded)
+	}
+
+	lines := strings.Split(content, "\n")
+	sourceFileCache.Store(key, lines)
+	return lines, nil
+}
+
+// conclusionEmoji maps a job or step conclusion to the emoji used in the
+// Markdown report's jobs table.
+func conclusionEmoji(conclusion string) string {
+	switch conclusion {
+	case "success":
+		return "✅"
+	case "failure":
+		return "❌"
+	case "cancelled":
+		return "⛔"
+	case "skipped":
+		return "⏭️"
+	case "neutral":
+		return "➖"
+	case "timed_out":
+		return "⏱️"
+	default:
+		return "⚪"
+	}
+}
+
+// renderRunMarkdown writes a self-contained Markdown report for run: a
+// title and triggering event, a table of jobs with conclusion emoji,
+// collapsible step listings for failed jobs, and a table of annotations
+// linking back to the offending line on GitHub. This is meant to be pasted
+// directly into a PR comment, issue, or Slack message.
+func renderRunMarkdown(out io.Writer, repo ghrepo.Interface, run shared.Run, jobs []shared.Job, annotations []shared.Annotation) error {
+	fmt.Fprintf(out, "# %s\n\n", run.Name)
+	fmt.Fprintf(out, "Triggered by **%s** on `%s`\n\n", run.Event, run.HeadBranch)
+
+	fmt.Fprintln(out, "| Job | Conclusion |")
+	fmt.Fprintln(out, "| --- | --- |")
+	for _, job := range jobs {
+		fmt.Fprintf(out, "| %s | %s %s |\n", job.Name, conclusionEmoji(job.Conclusion), job.Conclusion)
+	}
+	fmt.Fprintln(out)
+
+	for _, job := range jobs {
+		if !shared.IsFailureState(job.Conclusion) {
+			continue
+		}
+		fmt.Fprintf(out, "<details>\n<summary>%s (failed)</summary>\n\n", job.Name)
+		for _, step := range job.Steps {
+			fmt.Fprintf(out, "- %s %s\n", conclusionEmoji(step.Conclusion), step.Name)
+		}
+		fmt.Fprint(out, "\n</details>\n\n")
+	}
+
+	if len(annotations) > 0 {
+		fmt.Fprintln(out, "## Annotations")
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "| Message | Location |")
+		fmt.Fprintln(out, "| --- | --- |")
+		for _, a := range annotations {
+			location := fmt.Sprintf("%s:%d", a.Path, a.StartLine)
+			if a.Path != "" {
+				location = fmt.Sprintf("[%s](https://github.com/%s/%s/blob/%s/%s#L%d)",
+					location, repo.RepoOwner(), repo.RepoName(), run.HeadSha, a.Path, a.StartLine)
+			}
+			fmt.Fprintf(out, "| %s | %s |\n", a.Message, location)
+		}
+	}
+
+	return nil
+}
+
 // TODO consider context struct for all this:
 
-func renderRun(opts ViewOptions, run shared.Run, jobs []shared.Job, annotations []shared.Annotation) error {
+func renderRun(opts ViewOptions, client *api.Client, repo ghrepo.Interface, run shared.Run, jobs []shared.Job, annotations []shared.Annotation) error {
 	out := opts.IO.Out
 	cs := opts.IO.ColorScheme()
 
-	title := titleForRun(cs, run)
+	title := titleForRun(client, repo, cs, run, opts.NoPR)
 	symbol := shared.Symbol(cs, run.Status, run.Conclusion)
 	id := cs.Cyanf("%d", run.ID)
 
@@ -207,6 +651,10 @@ func renderRun(opts ViewOptions, run shared.Run, jobs []shared.Job, annotations
 			fmt.Fprintf(out, "%s %s\n", a.Symbol(cs), a.Message)
 			fmt.Fprintln(out, cs.Grayf("%s: %s#%d\n",
 				a.JobName, a.Path, a.StartLine))
+
+			if opts.ShowSource && a.Path != "" && a.StartLine > 0 {
+				renderAnnotationSource(out, cs, client, repo, run.HeadSha, a)
+			}
 		}
 	}
 
@@ -220,3 +668,125 @@ func renderRun(opts ViewOptions, run shared.Run, jobs []shared.Job, annotations
 
 	return nil
 }
+
+// followRun polls the run and its jobs until the run reaches a terminal
+// conclusion, streaming any newly produced log output from in-progress jobs
+// as it arrives. On a TTY the JOBS summary is redrawn in place on polls
+// that follow one with no new log output; after a poll that streamed log
+// lines below it, the fresh summary is printed after those lines instead.
+// On a non-TTY (e.g. piped to a file) it is simply appended, since there's
+// no cursor to rewind.
+func followRun(opts ViewOptions, client *api.Client, repo ghrepo.Interface, run *shared.Run) error {
+	out := opts.IO.Out
+	cs := opts.IO.ColorScheme()
+	isTTY := opts.IO.IsStdoutTTY()
+
+	offsets := map[int64]int64{}
+	linesRendered := 0
+	gotNewOutput := false
+	interval := pollIntervalMin
+
+	for {
+		jobs, err := shared.GetJobs(client, repo, *run)
+		if err != nil {
+			return fmt.Errorf("failed to get jobs: %w", err)
+		}
+
+		// Only rewind over the previous JOBS block when nothing was streamed
+		// below it; otherwise clearing would erase those log lines.
+		if isTTY && linesRendered > 0 && !gotNewOutput {
+			clearLines(out, linesRendered)
+		}
+		linesRendered = renderJobsSummary(out, cs, jobs)
+
+		gotNewOutput = false
+		for _, job := range jobs {
+			if job.Status == shared.Completed {
+				continue
+			}
+			lines, n, err := fetchNewJobLogLines(client, repo, job, offsets[job.ID])
+			if err != nil {
+				// A transient log-fetch error shouldn't abort a long-running
+				// follow; we'll just catch up on the next poll.
+				continue
+			}
+			if len(lines) == 0 {
+				continue
+			}
+			gotNewOutput = true
+			offsets[job.ID] += n
+			for _, line := range lines {
+				fmt.Fprintf(out, "%s %s\n", cs.Grayf("[%s]", job.Name), line)
+			}
+		}
+
+		run, err = shared.GetRun(client, repo, fmt.Sprintf("%d", run.ID))
+		if err != nil {
+			return fmt.Errorf("failed to get run: %w", err)
+		}
+		if run.Status == shared.Completed {
+			break
+		}
+
+		if gotNewOutput {
+			interval = pollIntervalMin
+		} else if interval *= 2; interval > pollIntervalMax {
+			interval = pollIntervalMax
+		}
+		time.Sleep(interval)
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "%s run %s\n", shared.Symbol(cs, run.Status, run.Conclusion), cs.Bold(titleForRun(client, repo, cs, *run, opts.NoPR)))
+
+	if opts.ExitStatus && shared.IsFailureState(run.Conclusion) {
+		return cmdutil.SilentError
+	}
+	return nil
+}
+
+// clearLines moves the cursor up n lines and erases each of them in place,
+// then returns the cursor to the first of those lines. Unlike \x1b[J (erase
+// to end of screen), this never touches anything printed below the n lines,
+// so log output streamed after the JOBS block on prior polls is left intact.
+func clearLines(out io.Writer, n int) {
+	fmt.Fprintf(out, "\x1b[%dA", n)
+	for i := 0; i < n; i++ {
+		fmt.Fprint(out, "\x1b[2K\x1b[1B")
+	}
+	fmt.Fprintf(out, "\x1b[%dA", n)
+}
+
+// renderJobsSummary prints the current JOBS block and returns the number of
+// lines written, so the caller can rewind the cursor past it on the next redraw.
+func renderJobsSummary(out io.Writer, cs *iostreams.ColorScheme, jobs []shared.Job) int {
+	fmt.Fprintln(out, cs.Bold("JOBS"))
+	for _, job := range jobs {
+		fmt.Fprintf(out, "%s %s\n", shared.Symbol(cs, job.Status, job.Conclusion), job.Name)
+	}
+	return len(jobs) + 1
+}
+
+// fetchNewJobLogLines returns the lines appended to job's log since offset,
+// along with the number of new bytes consumed, so the caller can advance its
+// per-job offset and avoid re-emitting output already shown. It fetches only
+// the bytes past offset via an HTTP Range request rather than redownloading
+// the whole (potentially multi-MB) log on every poll.
+func fetchNewJobLogLines(client *api.Client, repo ghrepo.Interface, job shared.Job, offset int64) ([]string, int64, error) {
+	r, err := shared.JobLogRange(client, repo, job, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer r.Close()
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(content) == 0 {
+		return nil, 0, nil
+	}
+
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	return lines, int64(len(content)), nil
+}
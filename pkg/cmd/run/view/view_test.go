@@ -0,0 +1,198 @@
+package view
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/cli/cli/api"
+	"github.com/cli/cli/internal/ghrepo"
+	"github.com/cli/cli/pkg/cmd/run/shared"
+	"github.com/cli/cli/pkg/cmdutil"
+	"github.com/cli/cli/pkg/httpmock"
+	"github.com/cli/cli/pkg/iostreams"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullRequestForRun_fallsBackToRESTWhenGraphQLFindsNothing(t *testing.T) {
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+
+	reg.Register(
+		httpmock.GraphQL(`query PullRequestForRunSHA\b`),
+		httpmock.StringResponse(`{"data":{"repository":{"object":{"associatedPullRequests":{"nodes":[]}}}}}`),
+	)
+	reg.Register(
+		httpmock.REST("GET", "search/issues"),
+		httpmock.JSONResponse(map[string]interface{}{
+			"items": []map[string]interface{}{
+				{"number": 42, "state": "OPEN"},
+			},
+		}),
+	)
+
+	client := api.NewClientFromHTTP(&http.Client{Transport: reg})
+	repo := ghrepo.New("OWNER", "REPO")
+
+	pr, err := pullRequestForRun(client, repo, shared.Run{ID: 101, HeadSha: "deadbeef"})
+	require.NoError(t, err)
+	require.NotNil(t, pr)
+	assert.Equal(t, 42, pr.Number)
+	assert.Equal(t, "OPEN", pr.State)
+}
+
+func TestPullRequestForRun_cachesGenuineMiss(t *testing.T) {
+	reg := &httpmock.Registry{}
+	defer reg.Verify(t)
+
+	reg.Register(
+		httpmock.GraphQL(`query PullRequestForRunSHA\b`),
+		httpmock.StringResponse(`{"data":{"repository":{"object":{"associatedPullRequests":{"nodes":[]}}}}}`),
+	)
+	reg.Register(
+		httpmock.REST("GET", "search/issues"),
+		httpmock.JSONResponse(map[string]interface{}{"items": []map[string]interface{}{}}),
+	)
+
+	client := api.NewClientFromHTTP(&http.Client{Transport: reg})
+	repo := ghrepo.New("OWNER", "REPO")
+	run := shared.Run{ID: 102, HeadSha: "cafed00d"}
+
+	pr, err := pullRequestForRun(client, repo, run)
+	require.NoError(t, err)
+	require.Nil(t, pr)
+
+	// Second call must be served from cache, not hit either endpoint again.
+	pr, err = pullRequestForRun(client, repo, run)
+	require.NoError(t, err)
+	require.Nil(t, pr)
+}
+
+// TestExportedRun_jsonFieldSelection exercises the actual --json flag wiring
+// (cmdutil.AddJSONFlags + Exporter.Write), not just json.Marshal's default
+// behavior: it asserts that requesting a subset of runFields produces an
+// object with exactly those keys, and that the nested jobs/annotations
+// fields surface correctly when requested.
+func TestExportedRun_jsonFieldSelection(t *testing.T) {
+	io, _, stdout, _ := iostreams.Test()
+	f := &cmdutil.Factory{IOStreams: io}
+
+	var opts *ViewOptions
+	cmd := NewCmdView(f, func(o *ViewOptions) error {
+		opts = o
+		return nil
+	})
+	cmd.SetArgs([]string{"123", "--json", "name,headBranch,jobs"})
+	cmd.SetOut(io.ErrOut)
+	cmd.SetErr(io.ErrOut)
+
+	require.NoError(t, cmd.Execute())
+	require.NotNil(t, opts)
+	require.NotNil(t, opts.Exporter)
+
+	run := shared.Run{ID: 7, Name: "CI", HeadBranch: "main", HeadSha: "deadbeef"}
+	jobs := []shared.Job{{ID: 1, Name: "build"}}
+	annotations := []shared.Annotation{{Message: "oops", Path: "main.go", StartLine: 10}}
+
+	require.NoError(t, opts.Exporter.Write(io, &exportedRun{Run: run, Jobs: jobs, Annotations: annotations}))
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &decoded))
+
+	assert.ElementsMatch(t, []string{"name", "headBranch", "jobs"}, keysOf(decoded))
+	assert.Equal(t, "CI", decoded["name"])
+	assert.Equal(t, "main", decoded["headBranch"])
+
+	jobsOut, ok := decoded["jobs"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, jobsOut, 1)
+	job, ok := jobsOut[0].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "build", job["name"])
+
+	// HeadSha and annotations weren't requested, so they must not leak into
+	// the output even though they're present on exportedRun.
+	assert.NotContains(t, decoded, "headSha")
+	assert.NotContains(t, decoded, "annotations")
+}
+
+func keysOf(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func TestPrStateColor(t *testing.T) {
+	tests := map[string]string{
+		"OPEN":   "green",
+		"MERGED": "magenta",
+		"CLOSED": "red",
+	}
+	for state, want := range tests {
+		if got := prStateColor(state); got != want {
+			t.Errorf("prStateColor(%q) = %q, want %q", state, got, want)
+		}
+	}
+}
+
+func TestExitStatusErr(t *testing.T) {
+	failed := shared.Run{Conclusion: shared.Failure}
+	passed := shared.Run{Conclusion: "success"}
+
+	assert.Equal(t, cmdutil.SilentError, exitStatusErr(&ViewOptions{ExitStatus: true}, failed))
+	assert.NoError(t, exitStatusErr(&ViewOptions{ExitStatus: true}, passed))
+	assert.NoError(t, exitStatusErr(&ViewOptions{ExitStatus: false}, failed))
+}
+
+func TestRenderRunMarkdown(t *testing.T) {
+	repo := ghrepo.New("OWNER", "REPO")
+	run := shared.Run{Name: "CI", Event: "push", HeadBranch: "main", HeadSha: "deadbeef"}
+	jobs := []shared.Job{
+		{Name: "build", Conclusion: "success"},
+		{Name: "test", Conclusion: "failure", Steps: []shared.Step{{Name: "go test", Conclusion: "failure"}}},
+	}
+	annotations := []shared.Annotation{
+		{Message: "unexpected EOF", Path: "main.go", StartLine: 12},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, renderRunMarkdown(&buf, repo, run, jobs, annotations))
+
+	out := buf.String()
+	assert.Contains(t, out, "# CI")
+	assert.Contains(t, out, "| build | ✅ success |")
+	assert.Contains(t, out, "| test | ❌ failure |")
+	assert.Contains(t, out, "<summary>test (failed)</summary>")
+	assert.Contains(t, out, "https://github.com/OWNER/REPO/blob/deadbeef/main.go#L12")
+}
+
+func TestClearLines(t *testing.T) {
+	var buf bytes.Buffer
+	clearLines(&buf, 3)
+
+	out := buf.String()
+	assert.Equal(t, "\x1b[3A\x1b[2K\x1b[1B\x1b[2K\x1b[1B\x1b[2K\x1b[1B\x1b[3A", out)
+	// Must never erase to end of screen: that would also wipe out log lines
+	// streamed below the JOBS block on earlier polls.
+	assert.NotContains(t, out, "\x1b[J")
+}
+
+func TestEscapeContentsPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"internal/run.go", "internal/run.go"},
+		{"has space.go", "has%20space.go"},
+		{"weird#name.go", "weird%23name.go"},
+	}
+	for _, tt := range tests {
+		if got := escapeContentsPath(tt.path); got != tt.want {
+			t.Errorf("escapeContentsPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}